@@ -0,0 +1,168 @@
+// Package adaptor implements Ed25519 adaptor signatures (a.k.a. "scriptless
+// scripts"), the building block used by cross-chain atomic swaps to tie the
+// release of a signature to the revelation of a discrete logarithm.
+//
+// A presignature s' is computed exactly like a normal Ed25519 signature
+// except that the public challenge point is offset by an adaptor point
+// T = t·B. Completing the presignature into a valid signature requires
+// knowledge of t, and the reverse operation (extracting t from a completed
+// signature and its presignature) is what lets the counterparty on the
+// other chain learn the secret once the adaptor-signed transaction is
+// published.
+package adaptor
+
+import (
+	"crypto/sha512"
+	"errors"
+
+	"github.com/ModChain/edwards25519"
+)
+
+// AdaptorSign produces a presignature for msg under priv, tied to the
+// adaptor point T. The returned R is the 32-byte nonce commitment and
+// sPrime is the 32-byte presignature scalar. Completing sPrime into a
+// valid Ed25519 signature requires adding the discrete log t of T via
+// AdaptorAdapt.
+func AdaptorSign(priv *edwards25519.PrivateKey, msg []byte, T *edwards25519.ExtendedGroupElement) (R, sPrime []byte, err error) {
+	if priv == nil || T == nil {
+		return nil, nil, errors.New("adaptor: nil argument")
+	}
+
+	seed := priv.Serialize()
+
+	h := sha512.New()
+	h.Write(seed)
+	digest := h.Sum(nil)
+
+	var a [32]byte
+	copy(a[:], digest[:32])
+	a[0] &= 248
+	a[31] &= 127
+	a[31] |= 64
+
+	var A edwards25519.ExtendedGroupElement
+	edwards25519.GeScalarMultBase(&A, &a)
+	var pub [32]byte
+	A.ToBytes(&pub)
+
+	rh := sha512.New()
+	rh.Write(digest[32:])
+	rh.Write(msg)
+	rDigest := rh.Sum(nil)
+
+	var r [32]byte
+	edwards25519.ScReduce(&r, to64(rDigest))
+
+	var Rpoint edwards25519.ExtendedGroupElement
+	edwards25519.GeScalarMultBase(&Rpoint, &r)
+	var Rbytes [32]byte
+	Rpoint.ToBytes(&Rbytes)
+
+	// e = H(R+T || A || msg), the standard Ed25519 challenge but bound to
+	// the nonce commitment offset by the adaptor point rather than R alone.
+	var RT edwards25519.ExtendedGroupElement
+	edwards25519.GeAdd(&RT, &Rpoint, T)
+	var RTbytes [32]byte
+	RT.ToBytes(&RTbytes)
+
+	eh := sha512.New()
+	eh.Write(RTbytes[:])
+	eh.Write(pub[:])
+	eh.Write(msg)
+	eDigest := eh.Sum(nil)
+
+	var e [32]byte
+	edwards25519.ScReduce(&e, to64(eDigest))
+
+	var s [32]byte
+	edwards25519.ScMulAdd(&s, &e, &a, &r)
+
+	return Rbytes[:], s[:], nil
+}
+
+// AdaptorVerify checks that sPrime is a valid presignature for msg under
+// pub, tied to the adaptor point T, by confirming s'·B == R + e·A where
+// e is derived from R+T rather than R alone.
+func AdaptorVerify(pub *edwards25519.PublicKey, msg []byte, T *edwards25519.ExtendedGroupElement, R, sPrime []byte) bool {
+	if pub == nil || T == nil || len(R) != 32 || len(sPrime) != 32 {
+		return false
+	}
+
+	var Rbytes, sBytes [32]byte
+	copy(Rbytes[:], R)
+	copy(sBytes[:], sPrime)
+
+	if !edwards25519.ScMinimal(&sBytes) {
+		return false
+	}
+
+	var Rpoint edwards25519.ExtendedGroupElement
+	if !Rpoint.FromBytes(&Rbytes) {
+		return false
+	}
+
+	var RT edwards25519.ExtendedGroupElement
+	edwards25519.GeAdd(&RT, &Rpoint, T)
+	var RTbytes [32]byte
+	RT.ToBytes(&RTbytes)
+
+	pubBytes := pub.Serialize()
+
+	eh := sha512.New()
+	eh.Write(RTbytes[:])
+	eh.Write(pubBytes)
+	eh.Write(msg)
+	eDigest := eh.Sum(nil)
+
+	var e [32]byte
+	edwards25519.ScReduce(&e, to64(eDigest))
+
+	var A edwards25519.ExtendedGroupElement
+	var pubArr [32]byte
+	copy(pubArr[:], pubBytes)
+	if !A.FromBytes(&pubArr) {
+		return false
+	}
+
+	// GeDoubleScalarMultVartime(r, a, A, b) computes r = a·A + b·B, so
+	// feeding it -e rather than e gives s'·B - e·A, which is what the
+	// s'·B == R + e·A check rearranges to.
+	var negE [32]byte
+	edwards25519.ScNeg(&negE, &e)
+
+	var check edwards25519.ExtendedGroupElement
+	edwards25519.GeDoubleScalarMultVartime(&check, &negE, &A, &sBytes)
+
+	var checkBytes [32]byte
+	check.ToBytes(&checkBytes)
+
+	return checkBytes == Rbytes
+}
+
+// AdaptorAdapt completes a presignature sPrime into a full Ed25519
+// signature scalar s, given the adaptor secret t such that T = t·B.
+func AdaptorAdapt(sPrime, t []byte) (s []byte) {
+	var sBytes, tBytes, out [32]byte
+	copy(sBytes[:], sPrime)
+	copy(tBytes[:], t)
+
+	edwards25519.ScAdd(&out, &sBytes, &tBytes)
+	return out[:]
+}
+
+// AdaptorExtract recovers the adaptor secret t from a completed signature
+// scalar s and the original presignature sPrime.
+func AdaptorExtract(sPrime, s []byte) (t []byte) {
+	var sBytes, sPrimeBytes, out [32]byte
+	copy(sBytes[:], s)
+	copy(sPrimeBytes[:], sPrime)
+
+	edwards25519.ScSub(&out, &sBytes, &sPrimeBytes)
+	return out[:]
+}
+
+func to64(b []byte) *[64]byte {
+	var out [64]byte
+	copy(out[:], b)
+	return &out
+}
@@ -0,0 +1,55 @@
+package adaptor
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/ModChain/edwards25519"
+)
+
+func TestAdaptorSignVerifyAdaptExtract(t *testing.T) {
+	_, privBytes, err := edwards25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	priv, err := edwards25519.PrivKeyFromSecret(privBytes[:32])
+	if err != nil {
+		t.Fatalf("PrivKeyFromSecret: %v", err)
+	}
+
+	pubX, pubY := priv.Public()
+	pub := edwards25519.NewPublicKey(pubX, pubY)
+
+	var tSecret [32]byte
+	if _, err := rand.Read(tSecret[:]); err != nil {
+		t.Fatalf("rand.Read: %v", err)
+	}
+	edwards25519.ScReduce(&tSecret, to64(tSecret[:]))
+
+	var T edwards25519.ExtendedGroupElement
+	edwards25519.GeScalarMultBase(&T, &tSecret)
+
+	msg := []byte("adaptor signature test message")
+
+	R, sPrime, err := AdaptorSign(priv, msg, &T)
+	if err != nil {
+		t.Fatalf("AdaptorSign: %v", err)
+	}
+
+	if !AdaptorVerify(pub, msg, &T, R, sPrime) {
+		t.Fatal("expected presignature to verify")
+	}
+
+	s := AdaptorAdapt(sPrime, tSecret[:])
+
+	recovered := AdaptorExtract(sPrime, s)
+	if toArray(recovered) != tSecret {
+		t.Fatal("extracted secret does not match original adaptor secret")
+	}
+}
+
+func toArray(b []byte) [32]byte {
+	var out [32]byte
+	copy(out[:], b)
+	return out
+}
@@ -0,0 +1,496 @@
+package adaptor
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha512"
+	"errors"
+	"math/big"
+
+	"github.com/ModChain/edwards25519"
+)
+
+// secp256k1Curve is any elliptic.Curve implementation for secp256k1; callers
+// supply their own (e.g. github.com/ModChain/secp256k1) so this package does
+// not have to depend on a specific one.
+type secp256k1Curve = elliptic.Curve
+
+// dleqBits is the number of bits decomposed by the proof. The edwards25519
+// order L is slightly above 2^252, so a scalar in [2^252, L) does not fit in
+// 252 bits; only the low 252 bits of x are decomposed and proven.
+const dleqBits = 252
+
+// edwardsOrder is L, the order of the edwards25519 prime-order subgroup.
+var edwardsOrder, _ = new(big.Int).SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+
+// challengeBytes bounds each bit-proof's Fiat-Shamir challenge to 128 bits,
+// comfortably below both edwardsOrder and a secp256k1 curve order, so a
+// challenge can be used directly against either curve's scalar field
+// without the two reductions disagreeing (see proveBit for where this
+// matters).
+const challengeBytes = 16
+
+// DLEQProof proves that a secret scalar x is simultaneously the discrete
+// log of an edwards25519 point X = x·B_ed and a secp256k1 point Y = x·B_secp,
+// without revealing x. It is built as 252 independent bit proofs, one per
+// bit of x: each proves that a pair of same-randomness Pedersen commitments
+// (one per curve, under an independent generator H with unknown discrete
+// log) opens to 0 or to 1 on *both* curves simultaneously, via a 2-branch
+// OR proof that shares a single nonce/response across both curves' halves
+// of each branch. That shared nonce is what ties the two curves' openings
+// to the same bit and the same blinding factor, rather than merely proving
+// each curve's commitment independently opens to *some* bit.
+type DLEQProof struct {
+	EdCommits   []*edwards25519.ExtendedGroupElement
+	SecpCommits []secpPoint
+
+	Bits []*bitProof
+}
+
+// bitProof is the 2-branch ring proof for a single bit, mirroring the
+// structure of ring.CLSAGSignature: C0 closes the loop, and S holds one
+// (edwards, secp256k1) response pair per branch (branch 0 = "bit is 0",
+// branch 1 = "bit is 1").
+type bitProof struct {
+	C0 [challengeBytes]byte
+	S  [2]branchResponse
+}
+
+type branchResponse struct {
+	Ed   *big.Int // response reduced mod edwardsOrder
+	Secp *big.Int // response reduced mod the secp256k1 curve order
+}
+
+// secpPoint is a minimal (X, Y) pair so this file does not need to import a
+// concrete secp256k1 implementation.
+type secpPoint struct {
+	X, Y *big.Int
+}
+
+// ProveDLEQ builds a cross-curve discrete-log-equality proof for the scalar
+// represented by the low 252 bits of x, returning the proof along with the
+// public points on both curves.
+func ProveDLEQ(curve secp256k1Curve, x *[32]byte) (proof *DLEQProof, edPoint *edwards25519.ExtendedGroupElement, secpPub secpPoint, err error) {
+	xInt := new(big.Int).SetBytes(reverse(x[:]))
+	if xInt.Sign() == 0 {
+		return nil, nil, secpPoint{}, errors.New("adaptor: zero scalar")
+	}
+
+	hSecp := hashToPointSecp(curve, []byte("ModChain/edwards25519 adaptor dleq H_secp"))
+
+	proof = &DLEQProof{
+		EdCommits:   make([]*edwards25519.ExtendedGroupElement, dleqBits),
+		SecpCommits: make([]secpPoint, dleqBits),
+		Bits:        make([]*bitProof, dleqBits),
+	}
+
+	var edAcc edwards25519.ExtendedGroupElement
+	edAcc.Zero()
+	secpAccX, secpAccY := curve.ScalarMult(curve.Params().Gx, curve.Params().Gy, big.NewInt(0).Bytes())
+
+	for i := 0; i < dleqBits; i++ {
+		bit := xInt.Bit(i)
+
+		r, err := randBelow(edwardsOrder)
+		if err != nil {
+			return nil, nil, secpPoint{}, err
+		}
+
+		edC, secpC := commitBit(curve, bit, r, hEd, hSecp)
+		proof.EdCommits[i] = edC
+		proof.SecpCommits[i] = secpC
+
+		bp, err := proveBit(curve, uint(i), bit, r, edC, secpC, hEd, hSecp)
+		if err != nil {
+			return nil, nil, secpPoint{}, err
+		}
+		proof.Bits[i] = bp
+
+		var scaled edwards25519.ExtendedGroupElement
+		edwards25519.GeScalarMult(&scaled, pow2Scalar(i), edC)
+		edwards25519.GeAdd(&edAcc, &edAcc, &scaled)
+
+		sx, sy := curve.ScalarMult(secpC.X, secpC.Y, pow2Bytes(i))
+		secpAccX, secpAccY = curve.Add(secpAccX, secpAccY, sx, sy)
+	}
+
+	return proof, &edAcc, secpPoint{secpAccX, secpAccY}, nil
+}
+
+// VerifyDLEQ checks proof against the claimed public points on both curves.
+func VerifyDLEQ(curve secp256k1Curve, proof *DLEQProof, edPoint *edwards25519.ExtendedGroupElement, secpPub secpPoint) bool {
+	if proof == nil || len(proof.EdCommits) != dleqBits || len(proof.SecpCommits) != dleqBits || len(proof.Bits) != dleqBits {
+		return false
+	}
+
+	hSecp := hashToPointSecp(curve, []byte("ModChain/edwards25519 adaptor dleq H_secp"))
+
+	var edAcc edwards25519.ExtendedGroupElement
+	edAcc.Zero()
+	secpAccX, secpAccY := curve.ScalarMult(curve.Params().Gx, curve.Params().Gy, big.NewInt(0).Bytes())
+
+	for i := 0; i < dleqBits; i++ {
+		if !verifyBit(curve, uint(i), proof.EdCommits[i], proof.SecpCommits[i], proof.Bits[i], hEd, hSecp) {
+			return false
+		}
+
+		var scaled edwards25519.ExtendedGroupElement
+		edwards25519.GeScalarMult(&scaled, pow2Scalar(i), proof.EdCommits[i])
+		edwards25519.GeAdd(&edAcc, &edAcc, &scaled)
+
+		sx, sy := curve.ScalarMult(proof.SecpCommits[i].X, proof.SecpCommits[i].Y, pow2Bytes(i))
+		secpAccX, secpAccY = curve.Add(secpAccX, secpAccY, sx, sy)
+	}
+
+	var edBytes, wantBytes [32]byte
+	edAcc.ToBytes(&edBytes)
+	edPoint.ToBytes(&wantBytes)
+
+	edOK := edBytes == wantBytes
+	secpOK := secpAccX.Cmp(secpPub.X) == 0 && secpAccY.Cmp(secpPub.Y) == 0
+	return edOK && secpOK
+}
+
+// commitBit builds the paired (edwards, secp256k1) Pedersen commitments
+// bit·G + r·H for a single bit, sharing the randomness r across both
+// curves and both using an independent, nothing-up-my-sleeve generator H
+// (derived by hashing to a point rather than as a known multiple of G, so
+// nobody knows its discrete log with respect to G).
+func commitBit(curve secp256k1Curve, bit uint, r *big.Int, hEd *edwards25519.ExtendedGroupElement, hSecp secpPoint) (*edwards25519.ExtendedGroupElement, secpPoint) {
+	rBytes := bigToLE32(r)
+
+	var edC edwards25519.ExtendedGroupElement
+	edwards25519.GeScalarMult(&edC, &rBytes, hEd)
+	if bit == 1 {
+		var g edwards25519.ExtendedGroupElement
+		var one [32]byte
+		one[0] = 1
+		edwards25519.GeScalarMultBase(&g, &one)
+		edwards25519.GeAdd(&edC, &edC, &g)
+	}
+
+	secpX, secpY := curve.ScalarMult(hSecp.X, hSecp.Y, r.Bytes())
+	if bit == 1 {
+		gx, gy := curve.Params().Gx, curve.Params().Gy
+		secpX, secpY = curve.Add(secpX, secpY, gx, gy)
+	}
+
+	return &edC, secpPoint{secpX, secpY}
+}
+
+// proveBit builds the 2-branch OR proof that (edC, secpC) opens to 0 or to
+// 1 on both curves simultaneously, for the real bit value and blinding
+// factor r. It follows the same "store C0, walk the ring" shape as
+// ring.SignCLSAG: a single random nonce k seeds the branch adjacent to the
+// real one, that branch is closed with a simulated transcript, and the
+// real branch's response closes the loop back to C0.
+func proveBit(curve secp256k1Curve, index uint, bit uint, r *big.Int, edC *edwards25519.ExtendedGroupElement, secpC secpPoint, hEd *edwards25519.ExtendedGroupElement, hSecp secpPoint) (*bitProof, error) {
+	k, err := randBelow(edwardsOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	other := 1 - bit
+
+	// Announce the real branch from the honest nonce k.
+	kBytes := bigToLE32(k)
+	var aEdReal edwards25519.ExtendedGroupElement
+	edwards25519.GeScalarMult(&aEdReal, &kBytes, hEd)
+	aSecpRealX, aSecpRealY := curve.ScalarMult(hSecp.X, hSecp.Y, k.Bytes())
+
+	cOther := bitChallenge(index, &aEdReal, aSecpRealX, aSecpRealY)
+
+	// Simulate the other branch: pick a response at random and solve for
+	// the announcement that makes the verification equation hold.
+	sOther, err := randBelow(edwardsOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	pEdOther, pSecpXOther, pSecpYOther := branchPoint(curve, edC, secpC, other)
+
+	aEdOther := simulateEdAnnouncement(sOther, cOther, hEd, pEdOther)
+	aSecpOtherX, aSecpOtherY := simulateSecpAnnouncement(curve, sOther, cOther, hSecp, pSecpXOther, pSecpYOther)
+
+	cReal := bitChallenge(index, aEdOther, aSecpOtherX, aSecpOtherY)
+
+	// Close the real branch: s_real = k - c_real * r.
+	sReal := new(big.Int).Mul(cReal, r)
+	sReal.Sub(k, sReal)
+
+	resp := [2]branchResponse{}
+	resp[bit] = branchResponse{
+		Ed:   new(big.Int).Mod(sReal, edwardsOrder),
+		Secp: new(big.Int).Mod(sReal, curve.Params().N),
+	}
+	resp[other] = branchResponse{
+		Ed:   new(big.Int).Mod(sOther, edwardsOrder),
+		Secp: new(big.Int).Mod(sOther, curve.Params().N),
+	}
+
+	var c0 [challengeBytes]byte
+	if bit == 0 {
+		copy(c0[:], bigToBE(cReal, challengeBytes))
+	} else {
+		copy(c0[:], bigToBE(cOther, challengeBytes))
+	}
+
+	return &bitProof{C0: c0, S: resp}, nil
+}
+
+// verifyBit walks the 2-branch ring forward from the stored C0 and checks
+// it closes back to C0, exactly as ring.VerifyCLSAG walks its n-branch
+// ring.
+func verifyBit(curve secp256k1Curve, index uint, edC *edwards25519.ExtendedGroupElement, secpC secpPoint, bp *bitProof, hEd *edwards25519.ExtendedGroupElement, hSecp secpPoint) bool {
+	if bp == nil {
+		return false
+	}
+
+	c := new(big.Int).SetBytes(bp.C0[:])
+
+	for j := 0; j < 2; j++ {
+		resp := bp.S[j]
+		if resp.Ed == nil || resp.Secp == nil {
+			return false
+		}
+
+		pEd, pSecpX, pSecpY := branchPoint(curve, edC, secpC, uint(j))
+
+		aEd := simulateEdAnnouncement(resp.Ed, c, hEd, pEd)
+		aSecpX, aSecpY := simulateSecpAnnouncement(curve, resp.Secp, c, hSecp, pSecpX, pSecpY)
+
+		c = bitChallenge(index, aEd, aSecpX, aSecpY)
+	}
+
+	return new(big.Int).SetBytes(bp.C0[:]).Cmp(c) == 0
+}
+
+// branchPoint returns branch·G subtracted from the commitment on each
+// curve: this is r·H if branch matches the committed bit, and an
+// unrelated point otherwise.
+func branchPoint(curve secp256k1Curve, edC *edwards25519.ExtendedGroupElement, secpC secpPoint, branch uint) (*edwards25519.ExtendedGroupElement, *big.Int, *big.Int) {
+	p := *edC
+	if branch == 1 {
+		var negG edwards25519.ExtendedGroupElement
+		var one, negOneScalar [32]byte
+		one[0] = 1
+		edwards25519.ScNeg(&negOneScalar, &one)
+		edwards25519.GeScalarMultBase(&negG, &negOneScalar)
+		edwards25519.GeAdd(&p, &p, &negG)
+	}
+
+	px, py := secpC.X, secpC.Y
+	if branch == 1 {
+		gx, gy := curve.Params().Gx, curve.Params().Gy
+		negGy := new(big.Int).Sub(curve.Params().P, gy)
+		px, py = curve.Add(px, py, gx, negGy)
+	}
+
+	return &p, px, py
+}
+
+// simulateEdAnnouncement computes s·H + c·P on edwards25519 (P = pEd here),
+// which is both how a real branch's announcement is checked and how a
+// simulated branch's announcement is constructed: GeDoubleScalarMultVartime
+// does not take an arbitrary second base point, so s·H is computed
+// separately from c·P and the two are added.
+func simulateEdAnnouncement(s, c *big.Int, h, p *edwards25519.ExtendedGroupElement) *edwards25519.ExtendedGroupElement {
+	sBytes := bigToLE32(new(big.Int).Mod(s, edwardsOrder))
+	cBytes := bigToLE32(new(big.Int).Mod(c, edwardsOrder))
+
+	var sH, cP edwards25519.ExtendedGroupElement
+	edwards25519.GeScalarMult(&sH, &sBytes, h)
+	edwards25519.GeScalarMult(&cP, &cBytes, p)
+
+	var a edwards25519.ExtendedGroupElement
+	edwards25519.GeAdd(&a, &sH, &cP)
+	return &a
+}
+
+// simulateSecpAnnouncement computes s·H + c·P on secp256k1.
+func simulateSecpAnnouncement(curve secp256k1Curve, s, c *big.Int, h secpPoint, px, py *big.Int) (*big.Int, *big.Int) {
+	sModN := new(big.Int).Mod(s, curve.Params().N)
+	cModN := new(big.Int).Mod(c, curve.Params().N)
+
+	shX, shY := curve.ScalarMult(h.X, h.Y, sModN.Bytes())
+	cpX, cpY := curve.ScalarMult(px, py, cModN.Bytes())
+
+	return curve.Add(shX, shY, cpX, cpY)
+}
+
+// bitChallenge derives the Fiat-Shamir challenge for one step of the ring,
+// bound to the bit index and both curves' announcement points so the
+// proof cannot be replayed across positions or curves.
+func bitChallenge(index uint, aEd *edwards25519.ExtendedGroupElement, aSecpX, aSecpY *big.Int) *big.Int {
+	var aEdBytes [32]byte
+	aEd.ToBytes(&aEdBytes)
+
+	h := sha512.New()
+	h.Write([]byte("ModChain/edwards25519 adaptor dleq bit"))
+	h.Write([]byte{byte(index), byte(index >> 8)})
+	h.Write(aEdBytes[:])
+	h.Write(aSecpX.Bytes())
+	h.Write(aSecpY.Bytes())
+	digest := h.Sum(nil)
+
+	return new(big.Int).SetBytes(digest[:challengeBytes])
+}
+
+func randBelow(max *big.Int) (*big.Int, error) {
+	return rand.Int(rand.Reader, max)
+}
+
+func bigToLE32(n *big.Int) [32]byte {
+	be := new(big.Int).Mod(n, edwardsOrder).Bytes()
+	var out [32]byte
+	for i, v := range be {
+		out[len(be)-1-i] = v
+	}
+	return out
+}
+
+func bigToBE(n *big.Int, size int) []byte {
+	b := n.Bytes()
+	out := make([]byte, size)
+	copy(out[size-len(b):], b)
+	return out
+}
+
+func pow2Scalar(i int) *[32]byte {
+	var out [32]byte
+	out[i/8] = 1 << uint(i%8)
+	return &out
+}
+
+func pow2Bytes(i int) []byte {
+	n := new(big.Int).Lsh(big.NewInt(1), uint(i))
+	return n.Bytes()
+}
+
+func reverse(b []byte) []byte {
+	out := make([]byte, len(b))
+	for i := range b {
+		out[i] = b[len(b)-1-i]
+	}
+	return out
+}
+
+// feD and feOne are the edwards25519 curve parameter d and the field
+// element 1, needed locally to decompress candidate points during
+// hash-to-point, mirroring ring.feD/ring.feOne.
+var feD = feFromDecimal("37095705934669439343138083508754565189542113879843219016388785533085940283555")
+var feOne = feFromDecimal("1")
+
+// hEd is the independent, nothing-up-my-sleeve edwards25519 generator used
+// to blind every bit commitment. It's derived once at package init rather
+// than per call, since it doesn't depend on the curve argument threaded
+// through ProveDLEQ/VerifyDLEQ.
+var hEd = hashToPointEdwards([]byte("ModChain/edwards25519 adaptor dleq H_ed"))
+
+// hashToPointEdwards derives a nothing-up-my-sleeve edwards25519 generator
+// from a domain string via try-and-increment: hash, attempt to decompress
+// the digest as an Edwards y-coordinate, and rehash on failure. This is the
+// same construction ring.hashToPoint uses for Monero key images, but
+// keyed by a fixed domain string rather than a public key, since here the
+// point itself (not just its relation to an input) is what's needed.
+func hashToPointEdwards(domain []byte) *edwards25519.ExtendedGroupElement {
+	counter := byte(0)
+	for {
+		h := sha512.New()
+		h.Write(domain)
+		h.Write([]byte{counter})
+		digest := h.Sum(nil)
+		digest[31] &= 0x7f
+
+		var y edwards25519.FieldElement
+		var yBytes [32]byte
+		copy(yBytes[:], digest[:32])
+		edwards25519.FeFromBytes(&y, &yBytes)
+
+		if p, ok := decompressEdwards(&y); ok {
+			var eight [32]byte
+			eight[0] = 8
+			var cleared edwards25519.ExtendedGroupElement
+			edwards25519.GeScalarMult(&cleared, &eight, p)
+			return &cleared
+		}
+
+		counter++
+	}
+}
+
+// decompressEdwards recovers the Edwards point with y-coordinate y, solving
+// x^2 = (y^2-1)/(d*y^2+1) via FeDivPowM1, the same construction
+// ring.decompress uses.
+func decompressEdwards(y *edwards25519.FieldElement) (*edwards25519.ExtendedGroupElement, bool) {
+	var y2, u, dy2, v edwards25519.FieldElement
+	edwards25519.FeSquare(&y2, y)
+	edwards25519.FeSub(&u, &y2, feOne)
+	edwards25519.FeMul(&dy2, feD, &y2)
+	edwards25519.FeAdd(&v, &dy2, feOne)
+
+	var x edwards25519.FieldElement
+	edwards25519.FeDivPowM1(&x, &u, &v)
+
+	var x2, check edwards25519.FieldElement
+	edwards25519.FeSquare(&x2, &x)
+	edwards25519.FeMul(&check, &x2, &v)
+
+	var checkBytes, uBytes [32]byte
+	edwards25519.FeToBytes(&checkBytes, &check)
+	edwards25519.FeToBytes(&uBytes, &u)
+	if checkBytes != uBytes {
+		return nil, false
+	}
+
+	var p edwards25519.ExtendedGroupElement
+	p.X, p.Y, p.Z = x, *y, *feOne
+	edwards25519.FeMul(&p.T, &x, y)
+	return &p, true
+}
+
+func feFromDecimal(dec string) *edwards25519.FieldElement {
+	n, ok := new(big.Int).SetString(dec, 10)
+	if !ok {
+		panic("adaptor: invalid constant " + dec)
+	}
+
+	be := n.Bytes()
+	var le [32]byte
+	for i, v := range be {
+		le[len(be)-1-i] = v
+	}
+
+	var fe edwards25519.FieldElement
+	edwards25519.FeFromBytes(&fe, &le)
+	return &fe
+}
+
+// hashToPointSecp derives a nothing-up-my-sleeve secp256k1 point from a
+// domain string via try-and-increment: hash, treat the digest as an
+// x-coordinate, and check whether x^3+7 is a quadratic residue.
+func hashToPointSecp(curve secp256k1Curve, domain []byte) secpPoint {
+	p := curve.Params().P
+	b := big.NewInt(7)
+
+	counter := byte(0)
+	for {
+		h := sha512.New()
+		h.Write(domain)
+		h.Write([]byte{counter})
+		digest := h.Sum(nil)
+
+		x := new(big.Int).Mod(new(big.Int).SetBytes(digest[:32]), p)
+
+		rhs := new(big.Int).Exp(x, big.NewInt(3), p)
+		rhs.Add(rhs, b)
+		rhs.Mod(rhs, p)
+
+		y := new(big.Int).ModSqrt(rhs, p)
+		if y != nil {
+			return secpPoint{X: x, Y: y}
+		}
+
+		counter++
+	}
+}
@@ -0,0 +1,160 @@
+package edwards25519
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+)
+
+// VerifyBatch checks many (pubkey, message, signature) triples at once
+// using the standard randomized batch-verification trick: rather than
+// checking s_i·B == R_i + e_i·A_i individually, it samples an independent
+// 128-bit scalar z_i per signature and checks the single combined equation
+//
+//	sum(z_i·s_i)·B == sum(z_i·R_i) + sum(z_i·e_i·A_i)
+//
+// which holds with overwhelming probability only if every individual
+// equation holds. Each signature's terms are computed with their own scalar
+// multiplications (z_i·R_i, and z_i·s_i·B - z_i·e_i·A_i) and then summed;
+// no multi-scalar multiplication is used, so a batch does no less point
+// arithmetic than checking each signature on its own.
+//
+// It returns allOK == true if every signature is valid. Otherwise it
+// bisects the batch to report the indices of the invalid signatures in
+// badIdx.
+func VerifyBatch(pubs []*PublicKey, msgs [][]byte, sigs []*Signature) (allOK bool, badIdx []int) {
+	n := len(pubs)
+	if len(msgs) != n || len(sigs) != n {
+		return false, nil
+	}
+	if n == 0 {
+		return true, nil
+	}
+
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+
+	if batchCheck(pubs, msgs, sigs, idx) {
+		return true, nil
+	}
+
+	return false, batchBisect(pubs, msgs, sigs, idx)
+}
+
+// batchCheck evaluates the combined batch equation over the signatures
+// named by idx, returning true only if all of them are valid.
+func batchCheck(pubs []*PublicKey, msgs [][]byte, sigs []*Signature, idx []int) bool {
+	var sumR, sumTerm ExtendedGroupElement
+	sumR.Zero()
+	sumTerm.Zero()
+
+	for _, i := range idx {
+		pub, msg, sig := pubs[i], msgs[i], sigs[i]
+		if pub == nil || sig == nil {
+			return false
+		}
+
+		pubBytes := pub.Serialize()
+		var pubArr [32]byte
+		copy(pubArr[:], pubBytes)
+
+		var A ExtendedGroupElement
+		if !A.FromBytes(&pubArr) {
+			return false
+		}
+
+		var Rbytes, sBytes [32]byte
+		copy(Rbytes[:], sig.Serialize()[:32])
+		copy(sBytes[:], sig.Serialize()[32:])
+		if !ScMinimal(&sBytes) {
+			return false
+		}
+
+		var R ExtendedGroupElement
+		if !R.FromBytes(&Rbytes) {
+			return false
+		}
+
+		h := sha512.New()
+		h.Write(Rbytes[:])
+		h.Write(pubArr[:])
+		h.Write(msg)
+		digest := h.Sum(nil)
+
+		var e [32]byte
+		ScReduce(&e, to64(digest))
+
+		z, err := batchScalar()
+		if err != nil {
+			return false
+		}
+
+		var zR ExtendedGroupElement
+		GeScalarMult(&zR, &z, &R)
+		GeAdd(&sumR, &sumR, &zR)
+
+		var zs [32]byte
+		ScMul(&zs, &z, &sBytes)
+
+		var ze, negZE [32]byte
+		ScMul(&ze, &z, &e)
+		ScNeg(&negZE, &ze)
+
+		var term ExtendedGroupElement
+		GeDoubleScalarMultVartime(&term, &negZE, &A, &zs)
+		GeAdd(&sumTerm, &sumTerm, &term)
+	}
+
+	var lhs, rhs [32]byte
+	sumR.ToBytes(&lhs)
+	sumTerm.ToBytes(&rhs)
+	return lhs == rhs
+}
+
+// batchBisect recursively splits idx in half, recursing into any half that
+// fails the combined check, until it isolates the individually-bad
+// signatures.
+func batchBisect(pubs []*PublicKey, msgs [][]byte, sigs []*Signature, idx []int) []int {
+	if len(idx) == 1 {
+		i := idx[0]
+		if verifySingle(pubs[i], msgs[i], sigs[i]) {
+			return nil
+		}
+		return []int{i}
+	}
+
+	mid := len(idx) / 2
+	left, right := idx[:mid], idx[mid:]
+
+	var bad []int
+	if !batchCheck(pubs, msgs, sigs, left) {
+		bad = append(bad, batchBisect(pubs, msgs, sigs, left)...)
+	}
+	if !batchCheck(pubs, msgs, sigs, right) {
+		bad = append(bad, batchBisect(pubs, msgs, sigs, right)...)
+	}
+	return bad
+}
+
+func verifySingle(pub *PublicKey, msg []byte, sig *Signature) bool {
+	return batchCheck([]*PublicKey{pub}, [][]byte{msg}, []*Signature{sig}, []int{0})
+}
+
+// batchScalar draws a fresh, unpredictable 128-bit scalar z for one
+// signature in the batch. Randomized batch verification is only sound if
+// the forger cannot predict z before fixing the (pub, msg, sig) tuples it
+// attacks, so z must come from a source outside the prover's control
+// rather than being derived from the tuple itself.
+func batchScalar() (z [32]byte, err error) {
+	if _, err := rand.Read(z[:16]); err != nil {
+		return z, err
+	}
+	return z, nil
+}
+
+func to64(b []byte) *[64]byte {
+	var out [64]byte
+	copy(out[:], b)
+	return &out
+}
@@ -0,0 +1,54 @@
+package edwards25519
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+func TestVerifyBatch(t *testing.T) {
+	const n = 8
+	pubs := make([]*PublicKey, n)
+	msgs := make([][]byte, n)
+	sigs := make([]*Signature, n)
+
+	for i := 0; i < n; i++ {
+		pub, priv, err := GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatalf("GenerateKey: %v", err)
+		}
+
+		msg := []byte("batch verify message")
+		msgs[i] = msg
+
+		sig := Sign(priv, msg)
+		sigs[i] = sig
+
+		pubKey, err := ParsePubKey(pub[:])
+		if err != nil {
+			t.Fatalf("ParsePubKey: %v", err)
+		}
+		pubs[i] = pubKey
+	}
+
+	ok, bad := VerifyBatch(pubs, msgs, sigs)
+	if !ok {
+		t.Fatalf("expected batch to verify, bad indices: %v", bad)
+	}
+
+	// Corrupt one signature and make sure it is isolated correctly.
+	corrupt := sigs[3].Serialize()
+	corrupt[0] ^= 0xff
+	badSig, err := ParseSignature(corrupt)
+	if err != nil {
+		t.Fatalf("ParseSignature: %v", err)
+	}
+	sigs[3] = badSig
+
+	ok, bad = VerifyBatch(pubs, msgs, sigs)
+	if ok {
+		t.Fatal("expected batch verification to fail")
+	}
+	if len(bad) != 1 || bad[0] != 3 {
+		t.Fatalf("expected bad index [3], got %v", bad)
+	}
+}
@@ -0,0 +1,172 @@
+package edwards25519
+
+import (
+	"crypto/sha512"
+	"errors"
+)
+
+// dom2 flag values per RFC 8032 §5.1.6: the signing/verification input is
+// always prefixed with "SigEd25519 no Ed25519 collisions" when a context or
+// the ph (prehash) variant is in play, followed by a flag byte (0 for
+// Ed25519ctx, 1 for Ed25519ph) and a context-length byte. Plain Sign/Verify
+// add no prefix at all.
+const (
+	domPh  = 1
+	domCtx = 0
+)
+
+var dom2Prefix = []byte("SigEd25519 no Ed25519 collisions")
+
+// ErrContextTooLong is returned when a context longer than 255 bytes is
+// passed to SignPh, VerifyPh, SignCtx or VerifyCtx.
+var ErrContextTooLong = errors.New("edwards25519: context must be 255 bytes or fewer")
+
+// dom2 builds the RFC 8032 §5.1.6 domain-separation prefix for the given
+// flag and context.
+func dom2(flag byte, ctx []byte) ([]byte, error) {
+	if len(ctx) > 255 {
+		return nil, ErrContextTooLong
+	}
+
+	out := make([]byte, 0, len(dom2Prefix)+2+len(ctx))
+	out = append(out, dom2Prefix...)
+	out = append(out, flag, byte(len(ctx)))
+	out = append(out, ctx...)
+	return out, nil
+}
+
+// SignPh signs prehash, the SHA-512 digest of the actual message, using
+// the Ed25519ph variant from RFC 8032 §5.1. ctx is an optional context
+// string of up to 255 bytes.
+func SignPh(priv *[PrivateKeySize]byte, prehash [64]byte, ctx []byte) ([]byte, error) {
+	prefix, err := dom2(domPh, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return signDom(priv, prehash[:], prefix)
+}
+
+// VerifyPh verifies a signature produced by SignPh.
+func VerifyPh(pub *[PublicKeySize]byte, prehash [64]byte, ctx []byte, sig []byte) bool {
+	prefix, err := dom2(domPh, ctx)
+	if err != nil {
+		return false
+	}
+	return verifyDom(pub, prehash[:], prefix, sig)
+}
+
+// SignCtx signs msg using the Ed25519ctx variant from RFC 8032 §5.1: pure
+// Ed25519 over msg, but with a non-empty context folded into the domain
+// separation prefix so signatures under different contexts cannot be
+// confused with one another.
+func SignCtx(priv *[PrivateKeySize]byte, msg []byte, ctx []byte) ([]byte, error) {
+	prefix, err := dom2(domCtx, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return signDom(priv, msg, prefix)
+}
+
+// VerifyCtx verifies a signature produced by SignCtx.
+func VerifyCtx(pub *[PublicKeySize]byte, msg []byte, ctx []byte, sig []byte) bool {
+	prefix, err := dom2(domCtx, ctx)
+	if err != nil {
+		return false
+	}
+	return verifyDom(pub, msg, prefix, sig)
+}
+
+// signDom is the common signing path for the context/prehash variants: it
+// mirrors Sign's nonce/challenge derivation but hashes prefix ahead of
+// every input that Sign would otherwise hash on its own.
+func signDom(priv *[PrivateKeySize]byte, msg []byte, prefix []byte) ([]byte, error) {
+	h := sha512.New()
+	h.Write(priv[:32])
+	digest := h.Sum(nil)
+
+	var a [32]byte
+	copy(a[:], digest[:32])
+	a[0] &= 248
+	a[31] &= 127
+	a[31] |= 64
+
+	prefixScalarInput := digest[32:]
+
+	rh := sha512.New()
+	rh.Write(prefix)
+	rh.Write(prefixScalarInput)
+	rh.Write(msg)
+	rDigest := rh.Sum(nil)
+
+	var r [32]byte
+	ScReduce(&r, to64(rDigest))
+
+	var R ExtendedGroupElement
+	GeScalarMultBase(&R, &r)
+	var Rbytes [32]byte
+	R.ToBytes(&Rbytes)
+
+	pubBytes := priv[32:]
+
+	eh := sha512.New()
+	eh.Write(prefix)
+	eh.Write(Rbytes[:])
+	eh.Write(pubBytes)
+	eh.Write(msg)
+	eDigest := eh.Sum(nil)
+
+	var e [32]byte
+	ScReduce(&e, to64(eDigest))
+
+	var s [32]byte
+	ScMulAdd(&s, &e, &a, &r)
+
+	sig := make([]byte, 64)
+	copy(sig[:32], Rbytes[:])
+	copy(sig[32:], s[:])
+	return sig, nil
+}
+
+// verifyDom is the common verification path for the context/prehash
+// variants.
+func verifyDom(pub *[PublicKeySize]byte, msg []byte, prefix []byte, sig []byte) bool {
+	if len(sig) != 64 {
+		return false
+	}
+
+	var Rbytes, sBytes [32]byte
+	copy(Rbytes[:], sig[:32])
+	copy(sBytes[:], sig[32:])
+	if !ScMinimal(&sBytes) {
+		return false
+	}
+
+	var A ExtendedGroupElement
+	if !A.FromBytes(pub) {
+		return false
+	}
+
+	eh := sha512.New()
+	eh.Write(prefix)
+	eh.Write(Rbytes[:])
+	eh.Write(pub[:])
+	eh.Write(msg)
+	eDigest := eh.Sum(nil)
+
+	var e [32]byte
+	ScReduce(&e, to64(eDigest))
+
+	// GeDoubleScalarMultVartime(r, a, A, b) computes r = a·A + b·B, so
+	// feeding it -e rather than e gives s·B - e·A, which is what the
+	// s·B == R + e·A check rearranges to.
+	var negE [32]byte
+	ScNeg(&negE, &e)
+
+	var check ExtendedGroupElement
+	GeDoubleScalarMultVartime(&check, &negE, &A, &sBytes)
+
+	var checkBytes [32]byte
+	check.ToBytes(&checkBytes)
+
+	return checkBytes == Rbytes
+}
@@ -0,0 +1,72 @@
+package edwards25519
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"testing"
+)
+
+func TestSignVerifyPh(t *testing.T) {
+	_, priv, err := GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	msg := []byte("prehashed message")
+	prehash := sha512.Sum512(msg)
+	ctx := []byte("test context")
+
+	sig, err := SignPh(priv, prehash, ctx)
+	if err != nil {
+		t.Fatalf("SignPh: %v", err)
+	}
+
+	var pub [PublicKeySize]byte
+	copy(pub[:], priv[32:])
+
+	if !VerifyPh(&pub, prehash, ctx, sig) {
+		t.Fatal("expected Ed25519ph signature to verify")
+	}
+
+	if VerifyPh(&pub, prehash, []byte("wrong context"), sig) {
+		t.Fatal("expected verification to fail under a different context")
+	}
+}
+
+func TestSignVerifyCtx(t *testing.T) {
+	_, priv, err := GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	msg := []byte("a message signed with a context")
+	ctx := []byte("test context")
+
+	sig, err := SignCtx(priv, msg, ctx)
+	if err != nil {
+		t.Fatalf("SignCtx: %v", err)
+	}
+
+	var pub [PublicKeySize]byte
+	copy(pub[:], priv[32:])
+
+	if !VerifyCtx(&pub, msg, ctx, sig) {
+		t.Fatal("expected Ed25519ctx signature to verify")
+	}
+
+	if VerifyCtx(&pub, msg, nil, sig) {
+		t.Fatal("expected verification to fail with an empty context")
+	}
+}
+
+func TestContextTooLong(t *testing.T) {
+	_, priv, err := GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	ctx := make([]byte, 256)
+	if _, err := SignCtx(priv, []byte("msg"), ctx); err != ErrContextTooLong {
+		t.Fatalf("expected ErrContextTooLong, got %v", err)
+	}
+}
@@ -0,0 +1,372 @@
+// Package ristretto255 implements the Ristretto prime-order group on top
+// of edwards25519. Ristretto maps the cofactor-8 edwards25519 curve onto a
+// prime-order group so protocols such as PAKEs, VRFs and confidential
+// transactions can use it without the cofactor pitfalls of working with
+// edwards25519 points directly.
+//
+// It is built directly on the module's FieldElement and
+// ExtendedGroupElement types, and reuses the (q-5)/8 exponentiation ladder
+// already present in FeDivPowM1 as the core of SqrtRatioM1, the inverse
+// square root helper the rest of the encode/decode/MAP logic is built on.
+package ristretto255
+
+import (
+	"math/big"
+
+	"github.com/ModChain/edwards25519"
+)
+
+// Element is a point in the Ristretto255 group.
+type Element struct {
+	p edwards25519.ExtendedGroupElement
+}
+
+// Identity returns the Ristretto255 identity element.
+func Identity() *Element {
+	e := new(Element)
+	e.p.Zero()
+	return e
+}
+
+// d, the edwards25519 curve parameter, and sqrt(-1) mod p are the only two
+// constants that cannot be derived from anything already in this package;
+// every other Ristretto constant below is computed from them.
+var (
+	feOne    = feFromDecimal("1")
+	feD      = feFromDecimal("37095705934669439343138083508754565189542113879843219016388785533085940283555")
+	feSqrtM1 = feFromDecimal("19681161376707505956807079304988542015446066515923890162744021073123829784752")
+
+	feOneMinusDSq    *edwards25519.FieldElement // 1 - d^2
+	feDMinusOneSq    *edwards25519.FieldElement // (d-1)^2
+	feInvSqrtAMinusD *edwards25519.FieldElement // 1/sqrt(a-d), a = -1
+	feSqrtADMinusOne *edwards25519.FieldElement // sqrt(a*d-1) = sqrt(-d-1)
+)
+
+func init() {
+	var dSq, oneMinusDSq edwards25519.FieldElement
+	edwards25519.FeSquare(&dSq, feD)
+	edwards25519.FeSub(&oneMinusDSq, feOne, &dSq)
+	feOneMinusDSq = &oneMinusDSq
+
+	var dMinus1, dMinus1Sq edwards25519.FieldElement
+	edwards25519.FeSub(&dMinus1, feD, feOne)
+	edwards25519.FeSquare(&dMinus1Sq, &dMinus1)
+	feDMinusOneSq = &dMinus1Sq
+
+	var negDMinus1 edwards25519.FieldElement // a*d - 1 = -d - 1
+	edwards25519.FeAdd(&negDMinus1, feD, feOne)
+	edwards25519.FeNeg(&negDMinus1, &negDMinus1)
+	sqrtADMinus1, _ := SqrtRatioM1(&negDMinus1, feOne)
+	feSqrtADMinusOne = sqrtADMinus1
+
+	var aMinusD edwards25519.FieldElement // a - d = -1 - d
+	edwards25519.FeAdd(&aMinusD, feD, feOne)
+	edwards25519.FeNeg(&aMinusD, &aMinusD)
+	invSqrtAMinusD, _ := SqrtRatioM1(feOne, &aMinusD)
+	feInvSqrtAMinusD = invSqrtAMinusD
+}
+
+// SqrtRatioM1 computes r = sqrt(u/v) when u/v is a square, following the
+// Ristretto255 SQRT_RATIO_M1 algorithm. It reuses FeDivPowM1 to compute the
+// candidate root u*v^3*(u*v^7)^((p-5)/8) and then corrects it: if
+// candidate^2 * v lands on u the candidate is kept, and if it lands on -u
+// multiplying by sqrt(-1) gives the root; in both cases wasSquare is true.
+// If it lands on -u*sqrt(-1), u/v is not a square: the candidate is still
+// multiplied by sqrt(-1), giving sqrt(sqrt(-1)*u/v) (the value the MAP
+// function uses), and wasSquare is false. The returned root is always the
+// non-negative one.
+func SqrtRatioM1(u, v *edwards25519.FieldElement) (r *edwards25519.FieldElement, wasSquare bool) {
+	var candidate edwards25519.FieldElement
+	edwards25519.FeDivPowM1(&candidate, u, v)
+
+	var check edwards25519.FieldElement
+	edwards25519.FeSquare(&check, &candidate)
+	edwards25519.FeMul(&check, &check, v)
+
+	var negU, negUSqrtM1 edwards25519.FieldElement
+	edwards25519.FeNeg(&negU, u)
+	edwards25519.FeMul(&negUSqrtM1, &negU, feSqrtM1)
+
+	correctSign := feEqual(&check, u)
+	flippedSign := feEqual(&check, &negU)
+	flippedSignI := feEqual(&check, &negUSqrtM1)
+
+	if flippedSign || flippedSignI {
+		edwards25519.FeMul(&candidate, &candidate, feSqrtM1)
+	}
+	if feIsNegative(&candidate) {
+		edwards25519.FeNeg(&candidate, &candidate)
+	}
+
+	return &candidate, correctSign || flippedSign
+}
+
+// Encode canonically encodes e into dst.
+func (e *Element) Encode(dst *[32]byte) {
+	X, Y, Z, T := e.p.X, e.p.Y, e.p.Z, e.p.T
+
+	var zPlusY, zMinusY, u1 edwards25519.FieldElement
+	edwards25519.FeAdd(&zPlusY, &Z, &Y)
+	edwards25519.FeSub(&zMinusY, &Z, &Y)
+	edwards25519.FeMul(&u1, &zPlusY, &zMinusY)
+
+	var u2, u2Sq edwards25519.FieldElement
+	edwards25519.FeMul(&u2, &X, &Y)
+	edwards25519.FeSquare(&u2Sq, &u2)
+
+	var v edwards25519.FieldElement
+	edwards25519.FeMul(&v, &u1, &u2Sq)
+	invSqrt, _ := SqrtRatioM1(feOne, &v)
+
+	var den1, den2 edwards25519.FieldElement
+	edwards25519.FeMul(&den1, invSqrt, &u1)
+	edwards25519.FeMul(&den2, invSqrt, &u2)
+
+	var zInv edwards25519.FieldElement
+	edwards25519.FeMul(&zInv, &den1, &den2)
+	edwards25519.FeMul(&zInv, &zInv, &T)
+
+	var ix, iy edwards25519.FieldElement
+	edwards25519.FeMul(&ix, &X, feSqrtM1)
+	edwards25519.FeMul(&iy, &Y, feSqrtM1)
+
+	var enchanted edwards25519.FieldElement
+	edwards25519.FeMul(&enchanted, &den1, feInvSqrtAMinusD)
+
+	var tZinv edwards25519.FieldElement
+	edwards25519.FeMul(&tZinv, &T, &zInv)
+	rotate := feIsNegative(&tZinv)
+
+	outX, outY, denInv := X, Y, den2
+	if rotate {
+		outX, outY, denInv = iy, ix, enchanted
+	}
+
+	var xZinv edwards25519.FieldElement
+	edwards25519.FeMul(&xZinv, &outX, &zInv)
+	if feIsNegative(&xZinv) {
+		edwards25519.FeNeg(&outY, &outY)
+	}
+
+	var s edwards25519.FieldElement
+	edwards25519.FeSub(&s, &Z, &outY)
+	edwards25519.FeMul(&s, &denInv, &s)
+	if feIsNegative(&s) {
+		edwards25519.FeNeg(&s, &s)
+	}
+
+	edwards25519.FeToBytes(dst, &s)
+}
+
+// Decode sets e to the element encoded by src and reports whether src was
+// a valid canonical Ristretto255 encoding.
+func (e *Element) Decode(src *[32]byte) bool {
+	var s edwards25519.FieldElement
+	edwards25519.FeFromBytes(&s, src)
+
+	var reencoded [32]byte
+	edwards25519.FeToBytes(&reencoded, &s)
+	if feIsNegative(&s) || reencoded != *src {
+		return false
+	}
+
+	var ss, u1, u2 edwards25519.FieldElement
+	edwards25519.FeSquare(&ss, &s)
+	edwards25519.FeSub(&u1, feOne, &ss)
+	edwards25519.FeAdd(&u2, feOne, &ss)
+
+	var u2Sq edwards25519.FieldElement
+	edwards25519.FeSquare(&u2Sq, &u2)
+
+	var u1Sq edwards25519.FieldElement
+	edwards25519.FeSquare(&u1Sq, &u1)
+
+	var dU1Sq edwards25519.FieldElement
+	edwards25519.FeMul(&dU1Sq, feD, &u1Sq)
+
+	var v edwards25519.FieldElement
+	edwards25519.FeNeg(&v, &dU1Sq)
+	edwards25519.FeSub(&v, &v, &u2Sq)
+
+	var vu2Sq edwards25519.FieldElement
+	edwards25519.FeMul(&vu2Sq, &v, &u2Sq)
+
+	invSqrt, wasSquare := SqrtRatioM1(feOne, &vu2Sq)
+	if !wasSquare {
+		return false
+	}
+
+	var denX, denY edwards25519.FieldElement
+	edwards25519.FeMul(&denX, invSqrt, &u2)
+	edwards25519.FeMul(&denY, &denX, invSqrt)
+	edwards25519.FeMul(&denY, &denY, &v)
+
+	var x edwards25519.FieldElement
+	edwards25519.FeMul(&x, &s, &denX)
+	edwards25519.FeAdd(&x, &x, &x)
+	if feIsNegative(&x) {
+		edwards25519.FeNeg(&x, &x)
+	}
+
+	var y edwards25519.FieldElement
+	edwards25519.FeMul(&y, &u1, &denY)
+
+	var t edwards25519.FieldElement
+	edwards25519.FeMul(&t, &x, &y)
+	if feIsNegative(&t) || feIsZero(&y) {
+		return false
+	}
+
+	e.p.X, e.p.Y, e.p.Z, e.p.T = x, y, *feOne, t
+	return true
+}
+
+// Add sets e = a + b and returns e.
+func (e *Element) Add(a, b *Element) *Element {
+	edwards25519.GeAdd(&e.p, &a.p, &b.p)
+	return e
+}
+
+// ScalarMult sets e = scalar * a and returns e.
+func (e *Element) ScalarMult(scalar *[32]byte, a *Element) *Element {
+	edwards25519.GeScalarMult(&e.p, scalar, &a.p)
+	return e
+}
+
+// ScalarBaseMult sets e = scalar * B, where B is the Ristretto255 base
+// point (the same generator used by edwards25519).
+func (e *Element) ScalarBaseMult(scalar *[32]byte) *Element {
+	edwards25519.GeScalarMultBase(&e.p, scalar)
+	return e
+}
+
+// Equal reports whether e and other encode to the same Ristretto255
+// element.
+func (e *Element) Equal(other *Element) bool {
+	var a, b [32]byte
+	e.Encode(&a)
+	other.Encode(&b)
+	return a == b
+}
+
+// FromUniformBytes maps a 64-byte uniformly-random string to a Ristretto255
+// element using the Elligator2-based MAP function, applied to each half of
+// the input and added together.
+func FromUniformBytes(b [64]byte) *Element {
+	var half1, half2 [32]byte
+	copy(half1[:], b[:32])
+	copy(half2[:], b[32:])
+
+	p1 := mapToPoint(&half1)
+	p2 := mapToPoint(&half2)
+
+	e := new(Element)
+	edwards25519.GeAdd(&e.p, &p1.p, &p2.p)
+	return e
+}
+
+// mapToPoint implements the Elligator2-based MAP function taking a single
+// 32-byte field element to a Ristretto255 element.
+func mapToPoint(t *[32]byte) *Element {
+	var tField edwards25519.FieldElement
+	edwards25519.FeFromBytes(&tField, t)
+
+	var r edwards25519.FieldElement
+	edwards25519.FeSquare(&r, &tField)
+	edwards25519.FeMul(&r, feSqrtM1, &r)
+
+	var rPlus1 edwards25519.FieldElement
+	edwards25519.FeAdd(&rPlus1, &r, feOne)
+
+	var u edwards25519.FieldElement
+	edwards25519.FeMul(&u, &rPlus1, feOneMinusDSq)
+
+	var rd, negOneMinusRd, rPlusD edwards25519.FieldElement
+	edwards25519.FeMul(&rd, &r, feD)
+	edwards25519.FeAdd(&negOneMinusRd, feOne, &rd)
+	edwards25519.FeNeg(&negOneMinusRd, &negOneMinusRd)
+	edwards25519.FeAdd(&rPlusD, &r, feD)
+
+	var v edwards25519.FieldElement
+	edwards25519.FeMul(&v, &negOneMinusRd, &rPlusD)
+
+	s, wasSquare := SqrtRatioM1(&u, &v)
+
+	var sPrime edwards25519.FieldElement
+	edwards25519.FeMul(&sPrime, s, &tField)
+	if !feIsNegative(&sPrime) {
+		edwards25519.FeNeg(&sPrime, &sPrime)
+	}
+	if !wasSquare {
+		*s = sPrime
+	}
+
+	var c edwards25519.FieldElement
+	if wasSquare {
+		edwards25519.FeNeg(&c, feOne)
+	} else {
+		c = r
+	}
+
+	var rMinus1, n edwards25519.FieldElement
+	edwards25519.FeSub(&rMinus1, &r, feOne)
+	edwards25519.FeMul(&n, &rMinus1, &c)
+	edwards25519.FeMul(&n, &n, feDMinusOneSq)
+	edwards25519.FeSub(&n, &n, &v)
+
+	var sSq edwards25519.FieldElement
+	edwards25519.FeSquare(&sSq, s)
+
+	var w0, w1, w2, w3 edwards25519.FieldElement
+	edwards25519.FeAdd(&w0, s, s)
+	edwards25519.FeMul(&w0, &w0, &v)
+	edwards25519.FeMul(&w1, &n, feSqrtADMinusOne)
+	edwards25519.FeSub(&w2, feOne, &sSq)
+	edwards25519.FeAdd(&w3, feOne, &sSq)
+
+	var x, y, z, tOut edwards25519.FieldElement
+	edwards25519.FeMul(&x, &w0, &w3)
+	edwards25519.FeMul(&y, &w2, &w1)
+	edwards25519.FeMul(&z, &w1, &w3)
+	edwards25519.FeMul(&tOut, &w0, &w2)
+
+	p := new(Element)
+	p.p.X, p.p.Y, p.p.Z, p.p.T = x, y, z, tOut
+	return p
+}
+
+func feEqual(a, b *edwards25519.FieldElement) bool {
+	var ab, bb [32]byte
+	edwards25519.FeToBytes(&ab, a)
+	edwards25519.FeToBytes(&bb, b)
+	return ab == bb
+}
+
+func feIsNegative(a *edwards25519.FieldElement) bool {
+	var b [32]byte
+	edwards25519.FeToBytes(&b, a)
+	return b[0]&1 == 1
+}
+
+func feIsZero(a *edwards25519.FieldElement) bool {
+	var b, zero [32]byte
+	edwards25519.FeToBytes(&b, a)
+	return b == zero
+}
+
+func feFromDecimal(dec string) *edwards25519.FieldElement {
+	n, ok := new(big.Int).SetString(dec, 10)
+	if !ok {
+		panic("ristretto255: invalid constant " + dec)
+	}
+
+	be := n.Bytes()
+	var le [32]byte
+	for i, v := range be {
+		le[len(be)-1-i] = v
+	}
+
+	var fe edwards25519.FieldElement
+	edwards25519.FeFromBytes(&fe, &le)
+	return &fe
+}
@@ -0,0 +1,55 @@
+package ristretto255
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestIdentityRoundTrip(t *testing.T) {
+	var enc [32]byte
+	Identity().Encode(&enc)
+
+	got := new(Element)
+	if !got.Decode(&enc) {
+		t.Fatal("failed to decode identity encoding")
+	}
+	if !got.Equal(Identity()) {
+		t.Fatal("decoded identity does not equal identity")
+	}
+}
+
+func TestScalarBaseMultRoundTrip(t *testing.T) {
+	var scalar [32]byte
+	scalar[0] = 7
+
+	p := new(Element).ScalarBaseMult(&scalar)
+
+	var enc [32]byte
+	p.Encode(&enc)
+
+	q := new(Element)
+	if !q.Decode(&enc) {
+		t.Fatal("failed to decode encoded point")
+	}
+	if !p.Equal(q) {
+		t.Fatal("decoded point does not equal original")
+	}
+}
+
+func TestFromUniformBytesDeterministic(t *testing.T) {
+	var input [64]byte
+	for i := range input {
+		input[i] = byte(i)
+	}
+
+	p1 := FromUniformBytes(input)
+	p2 := FromUniformBytes(input)
+
+	var e1, e2 [32]byte
+	p1.Encode(&e1)
+	p2.Encode(&e2)
+
+	if !bytes.Equal(e1[:], e2[:]) {
+		t.Fatal("FromUniformBytes is not deterministic")
+	}
+}